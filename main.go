@@ -2,45 +2,79 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
+	"io/fs"
 	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
 	"os/exec"
-	"path"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/BurntSushi/toml"
 	"github.com/shibukawa/configdir"
-	"golang.org/x/sys/windows/registry"
 )
 
-type Config struct {
+// OSConfig holds the platform-specific defaults for locating Unity
+// installs. Config embeds one OSConfig per supported OS so a single
+// config.toml can be shared across machines.
+type OSConfig struct {
 	ProgramDir string
 	DirPattern string
-	Versions   map[string]string
+}
+
+type Config struct {
+	Windows  OSConfig
+	Darwin   OSConfig
+	Linux    OSConfig
+	Versions map[string]string
+	Pinned   []string
+	// Rules maps a project path glob to a version constraint ("~2022.3"
+	// or "^2021.3.10f1") used to pick a fallback editor when the
+	// project's exact m_EditorVersion isn't installed.
+	Rules map[string]string
 }
 
 const vendorName = "hasht"
 const applicationName = "unity-version-selector"
 const configFile = "config.toml"
 const depthCutoff = 6
+const scanCacheFile = "scan-cache.json"
 const versionPattern = `m_EditorVersion: (.+)`
+const revisionPattern = `m_EditorVersionWithRevision: .+\((.+)\)`
+const unityDownloadBase = "https://download.unity3d.com/download_unity/"
 
 var defaultConfig = Config{
-	ProgramDir: "C:/Program Files",
-	DirPattern: `^Unity(.+)$`,
-	Versions:   map[string]string{},
+	Windows: OSConfig{
+		ProgramDir: "C:/Program Files",
+		DirPattern: `^Unity(.+)$`,
+	},
+	Darwin: OSConfig{
+		ProgramDir: "/Applications/Unity/Hub/Editor",
+		DirPattern: `^(.+)$`,
+	},
+	Linux: OSConfig{
+		ProgramDir: filepath.Join(os.Getenv("HOME"), "Unity/Hub/Editor"),
+		DirPattern: `^(.+)$`,
+	},
+	Versions: map[string]string{},
 }
 
 func main() {
 	reload := flag.Bool("reload", false, "Reload the Unity versions")
 	list := flag.Bool("list", false, "Show the list of the Unity versions")
+	install := flag.String("install", "", "Install a Unity version from Unity's download archive (<version>, or <version>/<changeset> to skip the changeset lookup)")
+	batch := flag.Bool("batch", false, "Use the numeric project picker instead of the interactive TUI")
+	strict := flag.Bool("strict", false, "Require the project's exact editor version; disable all fallback resolution ([Rules] and the default minor-line fallback)")
 	flag.Parse()
 
 	var project string
@@ -59,6 +93,25 @@ func main() {
 		config.initialize(*configDir)
 	}
 
+	if *install != "" {
+		version, hash := parseInstallArg(*install)
+		if hash == "" {
+			var err error
+			hash, err = lookupChangeset(version)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		if err := installEditor(version, hash, config.osConfig().ProgramDir); err != nil {
+			log.Fatal(err)
+		}
+
+		config.Versions = loadVersions(config, *configDir)
+		config.output(*configDir)
+		return
+	}
+
 	if *list {
 		for _, ver := range config.getVersionKeys() {
 			fmt.Println(ver, ":", config.Versions[ver])
@@ -67,16 +120,49 @@ func main() {
 	}
 
 	if project == "" {
-		project = askProject()
+		project = askProject(&config, *configDir, *batch)
+	}
+
+	config.openProject(project, *configDir, *strict, *batch)
+}
+
+// osConfig returns the OSConfig section matching the running platform.
+func (config Config) osConfig() OSConfig {
+	switch runtime.GOOS {
+	case "windows":
+		return config.Windows
+	case "darwin":
+		return config.Darwin
+	default:
+		return config.Linux
+	}
+}
+
+// askProject asks the user to pick a recent project, using the
+// interactive TUI when stdin is a TTY and falling back to the plain
+// numeric prompt otherwise (or when batch is set), so scripts keep
+// working unattended.
+func askProject(config *Config, configDir configdir.Config, batch bool) string {
+	items := buildProjectItems(*config, getRecentProjects())
+
+	if batch || !isInteractive() {
+		return askProjectFallback(items)
 	}
 
-	config.openProject(project)
+	return askProjectTUI(config, configDir, items)
 }
 
-func askProject() string {
-	recents := getRecentProjects()
-	for i, path := range recents {
-		println(i, ":", path)
+func isInteractive() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func askProjectFallback(items []projectItem) string {
+	for i, item := range items {
+		println(i, ":", item.path)
 	}
 
 	print("\n> ")
@@ -87,40 +173,37 @@ func askProject() string {
 	if err != nil {
 		log.Fatal(err)
 	}
-	if index < 0 || index >= len(recents) {
+	if index < 0 || index >= len(items) {
 		log.Fatal("The index is out of range")
 	}
 
-	return recents[index]
+	return items[index].path
 }
 
-func getRecentProjects() []string {
-	k, err := registry.OpenKey(registry.CURRENT_USER, `Software\Unity Technologies\Unity Editor 5.x`, registry.QUERY_VALUE)
-	if err != nil {
-		log.Fatal(err)
+// isPinned reports whether path has been pinned/favorited by the user.
+func (config Config) isPinned(path string) bool {
+	for _, p := range config.Pinned {
+		if p == path {
+			return true
+		}
 	}
-	defer k.Close()
+	return false
+}
 
-	names, err := k.ReadValueNames(0)
-	if err != nil {
-		log.Fatal(err)
+// togglePin pins path if it isn't pinned yet, or unpins it otherwise.
+func (config *Config) togglePin(path string) {
+	if !config.isPinned(path) {
+		config.Pinned = append(config.Pinned, path)
+		return
 	}
 
-	r := []string{}
-	for _, name := range names {
-		if !strings.HasPrefix(name, "RecentlyUsedProjectPaths") {
-			continue
+	pinned := []string{}
+	for _, p := range config.Pinned {
+		if p != path {
+			pinned = append(pinned, p)
 		}
-
-		val, _, err := k.GetBinaryValue(name)
-		if err != nil {
-			panic(err)
-		}
-
-		r = append(r, string(val[:len(val)-1]))
 	}
-
-	return r
+	config.Pinned = pinned
 }
 
 func getProjectVersion(projectPath string) string {
@@ -144,12 +227,172 @@ func getProjectVersion(projectPath string) string {
 	return string(s[1])
 }
 
-func (config Config) openProject(path string) {
+// tryGetProjectVersion is the non-fatal counterpart of getProjectVersion,
+// used when listing recent projects whose ProjectVersion.txt might be
+// missing or unreadable (e.g. a project that was since deleted).
+func tryGetProjectVersion(projectPath string) string {
+	p := filepath.Join(projectPath, "ProjectSettings/ProjectVersion.txt")
+
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		return ""
+	}
+
+	r := regexp.MustCompile(versionPattern)
+	s := r.FindSubmatch(data)
+	if len(s) < 2 {
+		return ""
+	}
+
+	return string(s[1])
+}
+
+// getProjectRevision extracts the changeset hash from the
+// m_EditorVersionWithRevision line of ProjectVersion.txt, if present.
+// It returns "" rather than failing, since the revision is only needed
+// as a hint for installing a missing editor.
+func getProjectRevision(projectPath string) string {
+	p := filepath.Join(projectPath, "ProjectSettings/ProjectVersion.txt")
+
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		return ""
+	}
+
+	r := regexp.MustCompile(revisionPattern)
+	s := r.FindSubmatch(data)
+	if len(s) < 2 {
+		return ""
+	}
+
+	return string(s[1])
+}
+
+// parseInstallArg splits a -install argument into a version and an
+// optional explicit changeset ("<version>/<changeset>"), the latter
+// only needed when lookupChangeset can't be reached (e.g. offline).
+func parseInstallArg(arg string) (version, hash string) {
+	parts := strings.SplitN(arg, "/", 2)
+	version = parts[0]
+	if len(parts) == 2 {
+		hash = parts[1]
+	}
+	return
+}
+
+// changesetLookupURL is Unity's public release API, used to resolve a
+// bare editor version to the changeset hash its download URL needs.
+const changesetLookupURL = "https://services.unity.com/api/unity/editor/release/v1/releases?version=%s"
+
+type releaseLookupResponse struct {
+	Results []struct {
+		ShortRevision string `json:"shortRevision"`
+	} `json:"results"`
+}
+
+// lookupChangeset resolves version to its changeset hash via Unity's
+// release API, so -install <version> works without the caller having
+// to dig a changeset out of a project themselves.
+func lookupChangeset(version string) (string, error) {
+	resp, err := http.Get(fmt.Sprintf(changesetLookupURL, version))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("looking up changeset for %s: %s", version, resp.Status)
+	}
+
+	var body releaseLookupResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if len(body.Results) == 0 || body.Results[0].ShortRevision == "" {
+		return "", fmt.Errorf("no changeset found for version %s", version)
+	}
+
+	return body.Results[0].ShortRevision, nil
+}
+
+// downloadInstaller fetches url into a temp file named filename and
+// returns its path.
+func downloadInstaller(url, filename string) (string, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("downloading %s: %s", url, resp.Status)
+	}
+
+	dest := filepath.Join(os.TempDir(), filename)
+	out, err := os.Create(dest)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		return "", err
+	}
+
+	return dest, nil
+}
+
+func (config *Config) openProject(path string, configDir configdir.Config, strict, batch bool) {
 	version := getProjectVersion(path)
+	chosen := version
 
 	exe, ok := config.Versions[version]
+	if !ok && !strict {
+		constraint, hasRule := config.ruleFor(path)
+		if !hasRule {
+			// No [Rules] glob covers this project: fall back to the
+			// newest installed patch of the exact version's own minor
+			// line, so "no exact match" still resolves something by
+			// default instead of requiring a rule to opt in.
+			if pv, ok := parseUnityVersion(version); ok {
+				constraint = fmt.Sprintf("~%d.%d", pv.major, pv.minor)
+				hasRule = true
+			}
+		}
+
+		if hasRule {
+			if resolved := resolveConstraint(constraint, config.Versions); resolved != "" {
+				chosen = resolved
+				exe, ok = config.Versions[resolved]
+			}
+		}
+	}
+
 	if !ok {
-		log.Fatal("The version not found")
+		hash := getProjectRevision(path)
+		if hash == "" {
+			log.Fatal("The version not found")
+		}
+
+		if !confirmInstall(version, batch) {
+			log.Fatal("The version not found")
+		}
+
+		log.Println(version, "is not installed; installing from Unity's download archive...")
+		if err := installEditor(version, hash, config.osConfig().ProgramDir); err != nil {
+			log.Fatal(err)
+		}
+
+		config.Versions = loadVersions(*config, configDir)
+		chosen = version
+		exe, ok = config.Versions[version]
+		if !ok {
+			log.Fatal("The version not found")
+		}
+	}
+
+	if chosen != version {
+		log.Printf("%s wants %s, which isn't installed; opening with %s instead\n", path, version, chosen)
 	}
 
 	cmd := exec.Command(exe, "-projectPath", path)
@@ -159,9 +402,44 @@ func (config Config) openProject(path string) {
 	}
 }
 
+// confirmInstall offers to install a missing editor version rather than
+// installing it unconditionally. In batch mode or when stdin isn't a
+// TTY there's no one to ask, so it proceeds automatically as scripted
+// use expects.
+func confirmInstall(version string, batch bool) bool {
+	if batch || !isInteractive() {
+		return true
+	}
+
+	fmt.Printf("%s is not installed. Install it now from Unity's download archive? [y/N] ", version)
+	stdin := bufio.NewScanner(os.Stdin)
+	stdin.Scan()
+
+	return strings.EqualFold(strings.TrimSpace(stdin.Text()), "y")
+}
+
+// ruleFor returns the constraint configured for projectPath under
+// [Rules], if any glob matches it. Patterns are tried in sorted order so
+// the result is deterministic when more than one matches.
+func (config Config) ruleFor(projectPath string) (string, bool) {
+	patterns := make([]string, 0, len(config.Rules))
+	for pattern := range config.Rules {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, projectPath); ok {
+			return config.Rules[pattern], true
+		}
+	}
+
+	return "", false
+}
+
 func (config *Config) initialize(configDir configdir.Config) {
 	*config = defaultConfig
-	(*config).Versions = loadVersions(*config)
+	(*config).Versions = loadVersions(*config, configDir)
 	config.output(configDir)
 }
 
@@ -177,90 +455,418 @@ func (config Config) output(configDir configdir.Config) {
 	}
 }
 
+// getVersionKeys returns config.Versions' keys ordered oldest to newest,
+// comparing Unity versions numerically (2022.3.9f1 before 2022.3.10f1)
+// rather than lexically. Keys that don't parse as a Unity version sort
+// before ones that do, keeping them grouped and stable.
 func (config Config) getVersionKeys() []string {
-	keys := []string{}
-	newKeys := []string{}
-	for key, _ := range config.Versions {
-		if strings.HasPrefix(key, "20") {
-			newKeys = append(newKeys, key)
-		} else {
-			keys = append(keys, key)
-		}
+	keys := make([]string, 0, len(config.Versions))
+	for key := range config.Versions {
+		keys = append(keys, key)
 	}
 
-	sort.Strings(keys)
-	sort.Strings(newKeys)
+	sort.Slice(keys, func(i, j int) bool {
+		vi, iok := parseUnityVersion(keys[i])
+		vj, jok := parseUnityVersion(keys[j])
 
-	keys = append(keys, newKeys...)
+		if iok && jok {
+			return compareUnityVersions(vi, vj) < 0
+		}
+		if iok != jok {
+			return !iok
+		}
+		return keys[i] < keys[j]
+	})
 
 	return keys
 }
 
-func loadVersions(config Config) map[string]string {
-	versions := map[string]string{}
-	r := regexp.MustCompile(config.DirPattern)
+// unityVersion is a parsed MAJOR.MINOR.PATCH<kind><build> Unity version,
+// e.g. 2022.3.10f1, kept numeric so versions compare correctly.
+type unityVersion struct {
+	major, minor, patch int
+	kind                string
+	build               int
+	raw                 string
+}
+
+var unityVersionPattern = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)([a-zA-Z]+)(\d+)$`)
 
-	files, err := ioutil.ReadDir(config.ProgramDir)
+func parseUnityVersion(s string) (unityVersion, bool) {
+	m := unityVersionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return unityVersion{}, false
+	}
+
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	patch, _ := strconv.Atoi(m[3])
+	build, _ := strconv.Atoi(m[5])
+
+	return unityVersion{major: major, minor: minor, patch: patch, kind: m[4], build: build, raw: s}, true
+}
+
+// compareUnityVersions returns <0, 0, >0 as a sorts before, equal to, or
+// after b.
+func compareUnityVersions(a, b unityVersion) int {
+	switch {
+	case a.major != b.major:
+		return a.major - b.major
+	case a.minor != b.minor:
+		return a.minor - b.minor
+	case a.patch != b.patch:
+		return a.patch - b.patch
+	case a.kind != b.kind:
+		return strings.Compare(a.kind, b.kind)
+	default:
+		return a.build - b.build
+	}
+}
+
+// resolveConstraint picks the best installed version satisfying
+// constraint, a "~2022.3" minor pin or a "^2021.3.10f1" floor, or ""
+// if nothing qualifies.
+func resolveConstraint(constraint string, versions map[string]string) string {
+	if len(constraint) < 2 {
+		return ""
+	}
+
+	spec := constraint[1:]
+	switch constraint[0] {
+	case '~':
+		return resolveTilde(spec, versions)
+	case '^':
+		return resolveCaret(spec, versions)
+	default:
+		return ""
+	}
+}
+
+// resolveTilde resolves a "~MAJOR.MINOR" constraint to the newest
+// installed patch of that minor line.
+func resolveTilde(spec string, versions map[string]string) string {
+	parts := strings.SplitN(spec, ".", 2)
+	if len(parts) != 2 {
+		return ""
+	}
+
+	major, err1 := strconv.Atoi(parts[0])
+	minor, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil {
+		return ""
+	}
+
+	var best unityVersion
+	found := false
+	for v := range versions {
+		pv, ok := parseUnityVersion(v)
+		if !ok || pv.major != major || pv.minor != minor {
+			continue
+		}
+		if !found || compareUnityVersions(pv, best) > 0 {
+			best, found = pv, true
+		}
+	}
+
+	if !found {
+		return ""
+	}
+	return best.raw
+}
+
+// resolveCaret resolves a "^MAJOR.MINOR.PATCHfN" constraint to the
+// newest installed version of the same major that's >= the floor.
+func resolveCaret(spec string, versions map[string]string) string {
+	floor, ok := parseUnityVersion(spec)
+	if !ok {
+		return ""
+	}
+
+	var best unityVersion
+	found := false
+	for v := range versions {
+		pv, ok := parseUnityVersion(v)
+		if !ok || pv.major != floor.major || compareUnityVersions(pv, floor) < 0 {
+			continue
+		}
+		if !found || compareUnityVersions(pv, best) > 0 {
+			best, found = pv, true
+		}
+	}
+
+	if !found {
+		return ""
+	}
+	return best.raw
+}
+
+// hubEditor is a single entry of Unity Hub's editors-v2.json. Location
+// is an array (Hub records one path per install location it knows
+// about) whose entries already point at the editor binary itself, not
+// the install's root dir.
+type hubEditor struct {
+	Version  string   `json:"version"`
+	Location []string `json:"location"`
+	Manual   bool     `json:"manual"`
+}
+
+// loadVersionsFromHub reads Unity Hub's own install manifest when one is
+// available for the running platform, so we don't have to rediscover
+// installs Hub already knows about.
+func loadVersionsFromHub() map[string]string {
+	p := hubEditorsJSONPath()
+	if p == "" || !isExists(p) {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(p)
 	if err != nil {
+		log.Println("failed to read Hub editors-v2.json:", err)
+		return nil
+	}
+
+	var entries map[string]hubEditor
+	if err := json.Unmarshal(data, &entries); err != nil {
+		log.Println("failed to parse Hub editors-v2.json:", err)
+		return nil
+	}
+
+	versions := map[string]string{}
+	for version, editor := range entries {
+		if len(editor.Location) == 0 {
+			continue
+		}
+
+		exe := editor.Location[0]
+		if !isExists(exe) {
+			continue
+		}
+		versions[version] = exe
+	}
+
+	return versions
+}
+
+// hubProjectsFile mirrors Unity Hub's projects-v1.json, which wraps the
+// project map under a top-level "data" object alongside a
+// "schema_version" field rather than storing it at the top level.
+type hubProjectsFile struct {
+	SchemaVersion string                     `json:"schema_version"`
+	Data          map[string]json.RawMessage `json:"data"`
+}
+
+// parseHubProjectPaths decodes a projects-v1.json payload into its
+// project paths, sorted so callers (notably the -batch numeric picker)
+// see stable indices across runs.
+func parseHubProjectPaths(data []byte) []string {
+	var file hubProjectsFile
+	if err := json.Unmarshal(data, &file); err != nil {
 		log.Fatal(err)
 	}
 
+	paths := make([]string, 0, len(file.Data))
+	for path := range file.Data {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// scanCacheEntry remembers where an install's editor executable was last
+// found, keyed to the install dir's mtime so unchanged dirs can skip the
+// scan on the next reload.
+type scanCacheEntry struct {
+	ModTime int64  `json:"modTime"`
+	Exe     string `json:"exe"`
+}
+
+type scanCache map[string]scanCacheEntry
+
+func scanCachePath(configDir configdir.Config) string {
+	return filepath.Join(configDir.Path, scanCacheFile)
+}
+
+func loadScanCache(configDir configdir.Config) scanCache {
+	cache := scanCache{}
+
+	data, err := ioutil.ReadFile(scanCachePath(configDir))
+	if err != nil {
+		return cache
+	}
+
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return scanCache{}
+	}
+
+	return cache
+}
+
+func (cache scanCache) save(configDir configdir.Config) {
+	data, err := json.Marshal(cache)
+	if err != nil {
+		log.Println("failed to marshal scan cache:", err)
+		return
+	}
+
+	if err := ioutil.WriteFile(scanCachePath(configDir), data, 0644); err != nil {
+		log.Println("failed to write scan cache:", err)
+	}
+}
+
+// loadVersions discovers installed editors by scanning
+// config.osConfig().ProgramDir, then filling in anything missed from
+// Unity Hub's own manifest. The scan always runs rather than being
+// skipped whenever Hub's manifest exists: right after this tool
+// installs an editor itself, Hub's manifest is still the one from
+// before that install, and only the scan will see the new one.
+func loadVersions(config Config, configDir configdir.Config) map[string]string {
+	osConfig := config.osConfig()
+	r := regexp.MustCompile(osConfig.DirPattern)
+	cache := loadScanCache(configDir)
+
+	// ProgramDir not existing isn't fatal now that this scan always
+	// runs: installs may be tracked entirely through Unity Hub's own
+	// manifest instead, e.g. Hub's default install location.
+	files, err := ioutil.ReadDir(osConfig.ProgramDir)
+	if err != nil {
+		files = nil
+	}
+
+	type found struct {
+		version string
+		dir     string
+		modTime int64
+		exe     string
+	}
+
+	results := make(chan found, len(files))
+	var wg sync.WaitGroup
+
 	for _, file := range files {
 		if !file.IsDir() {
 			continue
 		}
-		name := file.Name()
-		s := r.FindStringSubmatch(name)
-		if len(s) >= 2 {
-			p := path.Join(config.ProgramDir, name)
-			exe := deepFind(p, "Unity.exe")
-			if exe == "" {
-				log.Println(name, "has no Unity.exe!")
-				continue
-			}
-			versions[s[1]] = exe
+		s := r.FindStringSubmatch(file.Name())
+		if len(s) < 2 {
+			continue
+		}
+
+		version := s[1]
+		dir := filepath.Join(osConfig.ProgramDir, file.Name())
+		modTime := file.ModTime().Unix()
+
+		if entry, ok := cache[dir]; ok && entry.ModTime == modTime {
+			results <- found{version, dir, modTime, entry.Exe}
+			continue
+		}
+
+		wg.Add(1)
+		go func(version, dir string, modTime int64) {
+			defer wg.Done()
+			results <- found{version, dir, modTime, findEditor(dir)}
+		}(version, dir, modTime)
+	}
+
+	wg.Wait()
+	close(results)
+
+	versions := map[string]string{}
+	newCache := scanCache{}
+	for f := range results {
+		if f.exe == "" {
+			log.Println(f.version, "has no", filepath.Base(unityExecutableName)+"!")
+			continue
+		}
+		versions[f.version] = f.exe
+		newCache[f.dir] = scanCacheEntry{ModTime: f.modTime, Exe: f.exe}
+	}
+
+	newCache.save(configDir)
+
+	for version, exe := range loadVersionsFromHub() {
+		if _, ok := versions[version]; !ok {
+			versions[version] = exe
 		}
 	}
 
 	return versions
 }
 
-func isExists(path string) bool {
-	_, err := os.Stat(path)
-	return err == nil
+// findEditor locates the Unity executable inside an install dir, trying
+// the canonical Hub layout (<dir>/<unityExecutableName>) before falling
+// back to a bounded scan of its subdirectories.
+func findEditor(dir string) string {
+	canonical := filepath.Join(dir, unityExecutableName)
+	if isExists(canonical) {
+		return canonical
+	}
+
+	return scanForEditor(dir)
 }
 
-type dummyError struct{}
+// scanForEditor looks for name inside dir's direct subdirectories, one
+// worker per subdirectory, each bounded to depthCutoff levels below its
+// subdirectory.
+func scanForEditor(dir string) string {
+	name := filepath.Base(unityExecutableName)
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+
+	found := make(chan string, len(entries))
+	var wg sync.WaitGroup
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
 
-func (f dummyError) Error() string {
+		wg.Add(1)
+		go func(sub string) {
+			defer wg.Done()
+			if p := walkForFile(sub, name); p != "" {
+				found <- p
+			}
+		}(filepath.Join(dir, entry.Name()))
+	}
+
+	wg.Wait()
+	close(found)
+
+	for p := range found {
+		return p
+	}
 	return ""
 }
 
-func deepFind(root, name string) string {
+// walkForFile walks root looking for a file named name, stopping early
+// via fs.SkipAll once found and bailing out past depthCutoff levels.
+func walkForFile(root, name string) string {
 	sep := string(filepath.Separator)
+	baseDepth := strings.Count(root, sep)
 
 	var foundPath string
-
-	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
-			return err
+			return fs.SkipDir
 		}
-		depth := strings.Count(path, sep)
-		if depth > depthCutoff {
-			return filepath.SkipDir
+		if strings.Count(path, sep)-baseDepth > depthCutoff {
+			return fs.SkipDir
 		}
-
-		if !info.IsDir() && info.Name() == "Unity.exe" {
+		if !d.IsDir() && d.Name() == name {
 			foundPath = path
-			return dummyError{}
+			return fs.SkipAll
 		}
-
 		return nil
 	})
 
-	if err != (dummyError{}) {
-		log.Fatal(err)
-	}
-
 	return foundPath
 }
+
+func isExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
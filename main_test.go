@@ -0,0 +1,107 @@
+package main
+
+import "testing"
+
+func TestParseUnityVersion(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  unityVersion
+		valid bool
+	}{
+		{"2022.3.10f1", unityVersion{2022, 3, 10, "f", 1, "2022.3.10f1"}, true},
+		{"2021.3.10b1", unityVersion{2021, 3, 10, "b", 1, "2021.3.10b1"}, true},
+		{"5.6.0p4", unityVersion{5, 6, 0, "p", 4, "5.6.0p4"}, true},
+		{"2022.3", unityVersion{}, false},
+		{"not-a-version", unityVersion{}, false},
+		{"", unityVersion{}, false},
+	}
+
+	for _, test := range tests {
+		got, ok := parseUnityVersion(test.in)
+		if ok != test.valid {
+			t.Fatalf("parseUnityVersion(%q) ok = %v, want %v", test.in, ok, test.valid)
+		}
+		if ok && got != test.want {
+			t.Fatalf("parseUnityVersion(%q) = %+v, want %+v", test.in, got, test.want)
+		}
+	}
+}
+
+func TestCompareUnityVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"2022.3.9f1", "2022.3.10f1", -1}, // numeric, not lexical, patch compare
+		{"2022.3.10f1", "2022.3.9f1", 1},
+		{"2022.3.10f1", "2022.3.10f1", 0},
+		{"2021.3.10f1", "2022.3.10f1", -1}, // major wins
+		{"2022.2.10f1", "2022.3.10f1", -1}, // minor wins
+		{"2022.3.10b1", "2022.3.10f1", -1}, // kind compares lexically: b < f
+		{"2022.3.10f1", "2022.3.10f2", -1}, // build wins
+	}
+
+	for _, test := range tests {
+		a, _ := parseUnityVersion(test.a)
+		b, _ := parseUnityVersion(test.b)
+
+		got := compareUnityVersions(a, b)
+		switch {
+		case test.want < 0 && got >= 0:
+			t.Fatalf("compareUnityVersions(%s, %s) = %d, want < 0", test.a, test.b, got)
+		case test.want > 0 && got <= 0:
+			t.Fatalf("compareUnityVersions(%s, %s) = %d, want > 0", test.a, test.b, got)
+		case test.want == 0 && got != 0:
+			t.Fatalf("compareUnityVersions(%s, %s) = %d, want 0", test.a, test.b, got)
+		}
+	}
+}
+
+func TestResolveTilde(t *testing.T) {
+	versions := map[string]string{
+		"2022.3.9f1":  "/opt/unity/2022.3.9f1/Editor/Unity",
+		"2022.3.10f1": "/opt/unity/2022.3.10f1/Editor/Unity",
+		"2021.3.20f1": "/opt/unity/2021.3.20f1/Editor/Unity",
+	}
+
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"2022.3", "2022.3.10f1"}, // newest patch in the minor line
+		{"2021.3", "2021.3.20f1"},
+		{"2020.1", ""}, // no install in that minor line
+	}
+
+	for _, test := range tests {
+		got := resolveTilde(test.spec, versions)
+		if got != test.want {
+			t.Fatalf("resolveTilde(%q) = %q, want %q", test.spec, got, test.want)
+		}
+	}
+}
+
+func TestResolveCaret(t *testing.T) {
+	versions := map[string]string{
+		"2021.3.10f1": "/opt/unity/2021.3.10f1/Editor/Unity",
+		"2021.3.20f1": "/opt/unity/2021.3.20f1/Editor/Unity",
+		"2022.1.0f1":  "/opt/unity/2022.1.0f1/Editor/Unity",
+	}
+
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"2021.3.10f1", "2021.3.20f1"}, // newest at or above the floor, same major
+		{"2021.3.15f1", "2021.3.20f1"},
+		{"2021.3.25f1", ""}, // nothing meets the floor
+		{"2020.1.0f1", ""},  // no install of that major at all
+	}
+
+	for _, test := range tests {
+		got := resolveCaret(test.spec, versions)
+		if got != test.want {
+			t.Fatalf("resolveCaret(%q) = %q, want %q", test.spec, got, test.want)
+		}
+	}
+}
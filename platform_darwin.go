@@ -0,0 +1,62 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+const unityExecutableName = "Unity.app/Contents/MacOS/Unity"
+
+func installerURL(version, hash string) string {
+	return fmt.Sprintf("%s%s/MacEditorInstaller/Unity-%s.pkg", unityDownloadBase, hash, version)
+}
+
+// installEditor downloads the macOS .pkg installer and runs it silently
+// via the system installer(8) command, as Unity Hub does. programDir is
+// unused here: the .pkg always installs to its own default location,
+// unlike the Linux archive extraction.
+func installEditor(version, hash, programDir string) error {
+	installer, err := downloadInstaller(installerURL(version, hash), "Unity-"+version+".pkg")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(installer)
+
+	return exec.Command("installer", "-pkg", installer, "-target", "/").Run()
+}
+
+func hubEditorsJSONPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		log.Fatal(err)
+	}
+	return filepath.Join(home, "Library/Application Support/Unity/Hub/Editor/editors-v2.json")
+}
+
+func recentProjectsJSONPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		log.Fatal(err)
+	}
+	return filepath.Join(home, "Library/Application Support/UnityHub/projects-v1.json")
+}
+
+func getRecentProjects() []string {
+	p := recentProjectsJSONPath()
+	if !isExists(p) {
+		return []string{}
+	}
+
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return parseHubProjectPaths(data)
+}
@@ -0,0 +1,72 @@
+//go:build linux
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+const unityExecutableName = "Editor/Unity"
+
+func installerURL(version, hash string) string {
+	return fmt.Sprintf("%s%s/LinuxEditorInstaller/Unity-%s.tar.xz", unityDownloadBase, hash, version)
+}
+
+// installEditor downloads the Linux archive and extracts it under
+// programDir (the active config's ProgramDir), since Unity ships no
+// Linux installer binary.
+func installEditor(version, hash, programDir string) error {
+	archive, err := downloadInstaller(installerURL(version, hash), "Unity-"+version+".tar.xz")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(archive)
+
+	dest := filepath.Join(programDir, version)
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return err
+	}
+
+	return exec.Command("tar", "-xJf", archive, "-C", dest).Run()
+}
+
+// xdgConfigHome returns $XDG_CONFIG_HOME, falling back to ~/.config as
+// the XDG base directory spec requires.
+func xdgConfigHome() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		log.Fatal(err)
+	}
+	return filepath.Join(home, ".config")
+}
+
+func hubEditorsJSONPath() string {
+	return filepath.Join(xdgConfigHome(), "UnityHub/editors-v2.json")
+}
+
+func recentProjectsJSONPath() string {
+	return filepath.Join(xdgConfigHome(), "UnityHub/projects-v1.json")
+}
+
+func getRecentProjects() []string {
+	p := recentProjectsJSONPath()
+	if !isExists(p) {
+		return []string{}
+	}
+
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return parseHubProjectPaths(data)
+}
@@ -0,0 +1,71 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+const unityExecutableName = "Editor/Unity.exe"
+
+func installerURL(version, hash string) string {
+	return fmt.Sprintf("%s%s/Windows64EditorInstaller/UnitySetup64-%s.exe", unityDownloadBase, hash, version)
+}
+
+// installEditor downloads the Windows Unity installer and runs it
+// silently (NSIS's /S flag), the same invocation Unity Hub itself uses.
+// programDir is unused here: the installer always targets its own
+// default install location, unlike the Linux archive extraction.
+func installEditor(version, hash, programDir string) error {
+	installer, err := downloadInstaller(installerURL(version, hash), "UnitySetup64-"+version+".exe")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(installer)
+
+	return exec.Command(installer, "/S").Run()
+}
+
+// hubEditorsJSONPath returns "" on Windows: Unity Hub keeps no per-user
+// editors manifest there, so installs are found by scanning ProgramDir.
+func hubEditorsJSONPath() string {
+	return ""
+}
+
+func getRecentProjects() []string {
+	k, err := registry.OpenKey(registry.CURRENT_USER, `Software\Unity Technologies\Unity Editor 5.x`, registry.QUERY_VALUE)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer k.Close()
+
+	names, err := k.ReadValueNames(0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	r := []string{}
+	for _, name := range names {
+		if !strings.HasPrefix(name, "RecentlyUsedProjectPaths") {
+			continue
+		}
+
+		val, _, err := k.GetBinaryValue(name)
+		if err != nil {
+			panic(err)
+		}
+
+		r = append(r, string(val[:len(val)-1]))
+	}
+
+	sort.Strings(r)
+
+	return r
+}
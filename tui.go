@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shibukawa/configdir"
+)
+
+// projectItem is a recent project as shown in the picker, annotated with
+// its resolved Unity version and pin/availability state.
+type projectItem struct {
+	path    string
+	version string
+	pinned  bool
+	missing bool // required editor isn't in config.Versions
+}
+
+// buildProjectItems resolves each recent project's Unity version and
+// pairs it with its pin/availability state, pinned projects first.
+func buildProjectItems(config Config, paths []string) []projectItem {
+	pinned := []projectItem{}
+	rest := []projectItem{}
+
+	for _, path := range paths {
+		version := tryGetProjectVersion(path)
+		_, ok := config.Versions[version]
+		item := projectItem{
+			path:    path,
+			version: version,
+			pinned:  config.isPinned(path),
+			missing: version != "" && !ok,
+		}
+
+		if item.pinned {
+			pinned = append(pinned, item)
+		} else {
+			rest = append(rest, item)
+		}
+	}
+
+	return append(pinned, rest...)
+}
+
+func projectPaths(items []projectItem) []string {
+	paths := make([]string, len(items))
+	for i, item := range items {
+		paths[i] = item.path
+	}
+	return paths
+}
+
+// pickerModel is a bubbletea model that lets the user filter recent
+// projects by name/path/version, pin favorites, and pick one to open.
+type pickerModel struct {
+	config   *Config
+	items    []projectItem
+	filtered []projectItem
+	filter   string
+	cursor   int
+	choice   string
+}
+
+func newPickerModel(config *Config, items []projectItem) pickerModel {
+	m := pickerModel{config: config, items: items}
+	m.applyFilter()
+	return m
+}
+
+func (m *pickerModel) applyFilter() {
+	if m.filter == "" {
+		m.filtered = m.items
+	} else {
+		needle := strings.ToLower(m.filter)
+		m.filtered = []projectItem{}
+		for _, item := range m.items {
+			haystack := strings.ToLower(item.path + " " + item.version)
+			if strings.Contains(haystack, needle) {
+				m.filtered = append(m.filtered, item)
+			}
+		}
+	}
+
+	if m.cursor >= len(m.filtered) {
+		m.cursor = len(m.filtered) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+func (m pickerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m pickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c", "esc":
+		return m, tea.Quit
+	case "enter":
+		if len(m.filtered) > 0 {
+			m.choice = m.filtered[m.cursor].path
+		}
+		return m, tea.Quit
+	case "up", "ctrl+k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "ctrl+j":
+		if m.cursor < len(m.filtered)-1 {
+			m.cursor++
+		}
+	case "ctrl+p":
+		if len(m.filtered) > 0 {
+			m.config.togglePin(m.filtered[m.cursor].path)
+			m.items = buildProjectItems(*m.config, projectPaths(m.items))
+			m.applyFilter()
+		}
+	case "backspace":
+		if len(m.filter) > 0 {
+			m.filter = m.filter[:len(m.filter)-1]
+			m.applyFilter()
+		}
+	default:
+		if len(keyMsg.Runes) > 0 {
+			m.filter += string(keyMsg.Runes)
+			m.applyFilter()
+		}
+	}
+
+	return m, nil
+}
+
+func (m pickerModel) View() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Filter: %s\n\n", m.filter)
+
+	for i, item := range m.filtered {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+
+		pin := " "
+		if item.pinned {
+			pin = "*"
+		}
+
+		status := ""
+		if item.missing {
+			status = " (editor not installed)"
+		}
+
+		fmt.Fprintf(&b, "%s%s %s [%s]%s\n", cursor, pin, item.path, item.version, status)
+	}
+
+	b.WriteString("\n(type to filter, ctrl+p to pin, enter to open, esc to quit)\n")
+
+	return b.String()
+}
+
+// askProjectTUI runs the interactive picker and persists any pin changes
+// made along the way, regardless of whether a project was chosen.
+func askProjectTUI(config *Config, configDir configdir.Config, items []projectItem) string {
+	result, err := tea.NewProgram(newPickerModel(config, items)).Run()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	config.output(configDir)
+
+	final := result.(pickerModel)
+	if final.choice == "" {
+		log.Fatal("No project selected")
+	}
+
+	return final.choice
+}